@@ -0,0 +1,233 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// Adaptor converts a handler function of some arbitrary signature into an
+// http.Handler. Group.HandleFunc consults the registered adaptors once, at
+// registration time, so that signature matching never runs on the request
+// path. The built-in adaptors other than the JSON one return a handler that
+// calls fn directly; the JSON adaptor calls fn through reflect.Value.Call on
+// every request (see jsonAdaptor).
+type Adaptor interface {
+	// Match reports whether fn has a signature this adaptor knows how to
+	// adapt.
+	Match(fn any) bool
+
+	// Adapt builds an http.Handler from fn. It is only ever called after
+	// Match has reported true for the same value.
+	Adapt(fn any) (http.Handler, error)
+}
+
+var adaptors []Adaptor
+
+// RegisterAdaptor adds a to the set consulted by Group.HandleFunc. Adaptors
+// registered later are matched first, so a later registration can override a
+// built-in adaptor for a signature it also recognises. RegisterAdaptor is a
+// package-level registry change, not tied to any one Group, so unlike the
+// Group misconfigurations that accumulate into Build's error it panics
+// immediately.
+func RegisterAdaptor(a Adaptor) {
+	if a == nil {
+		panic(errNilAdaptor)
+	}
+	adaptors = append(adaptors, a)
+}
+
+// HandleFunc adapts fn using the most recently registered Adaptor whose Match
+// reports true, wraps the result with mws and adds it to the group as a
+// route served at path. fn must match one of the signatures accepted by a
+// registered Adaptor; built-in adaptors cover plain http.Handler,
+// http.HandlerFunc, func(error) http.Handler and JSON-in/JSON-out handlers
+// of the form func(context.Context, *Req) (*Resp, error). Mismatches are
+// recorded against the group and surfaced by Build, the same as every other
+// misuse in this package.
+func (g *Group) HandleFunc(path string, fn any, mws ...Middleware) *Group {
+	if fn == nil {
+		g.fail(errNilHandlerFunc)
+		return g
+	}
+
+	var handler http.Handler
+	for i := len(adaptors) - 1; i >= 0; i-- {
+		if adaptors[i].Match(fn) {
+			h, err := adaptors[i].Adapt(fn)
+			if err != nil {
+				g.fail(err)
+				return g
+			}
+			handler = h
+			break
+		}
+	}
+	if handler == nil {
+		g.fail(fmt.Errorf("%T: %w", fn, errNoAdaptor))
+		return g
+	}
+
+	if len(mws) > 0 {
+		handler = Wrap(mws...)(handler)
+	}
+	g.routes = append(g.routes, Route{Path: path, Handler: handler})
+	return g
+}
+
+// PathParams gives typed-handler signatures access to the wildcard values of
+// a Go 1.22 ServeMux pattern without taking a dependency on *http.Request.
+type PathParams struct {
+	r *http.Request
+}
+
+// Get returns the value matched by the named wildcard, or the empty string
+// if it was not present in the route pattern.
+func (p PathParams) Get(name string) string {
+	return p.r.PathValue(name)
+}
+
+// HTTPError lets a handler func's returned error carry its own HTTP status
+// code; the JSON adaptor uses it to derive the response status, falling
+// back to 500 for plain errors.
+type HTTPError interface {
+	error
+	StatusCode() int
+}
+
+var (
+	errType           = reflect.TypeOf((*error)(nil)).Elem()
+	httpHandlerType   = reflect.TypeOf((*http.Handler)(nil)).Elem()
+	contextType       = reflect.TypeOf((*context.Context)(nil)).Elem()
+	responseWriteType = reflect.TypeOf((*http.ResponseWriter)(nil)).Elem()
+	requestType       = reflect.TypeOf((*http.Request)(nil))
+	pathParamsType    = reflect.TypeOf(PathParams{})
+)
+
+// handlerAdaptor adapts an already-built http.Handler; it exists so that
+// values satisfying the interface pass straight through HandleFunc.
+type handlerAdaptor struct{}
+
+func (handlerAdaptor) Match(fn any) bool {
+	_, ok := fn.(http.Handler)
+	return ok
+}
+
+func (handlerAdaptor) Adapt(fn any) (http.Handler, error) {
+	return fn.(http.Handler), nil
+}
+
+// handlerFuncAdaptor adapts func(http.ResponseWriter, *http.Request), the
+// shape of http.HandlerFunc, without requiring callers to convert it first.
+type handlerFuncAdaptor struct{}
+
+func (handlerFuncAdaptor) Match(fn any) bool {
+	fnc, ok := fn.(func(http.ResponseWriter, *http.Request))
+	return ok && fnc != nil
+}
+
+func (handlerFuncAdaptor) Adapt(fn any) (http.Handler, error) {
+	return http.HandlerFunc(fn.(func(http.ResponseWriter, *http.Request))), nil
+}
+
+// errorFactoryAdaptor adapts func(error) http.Handler, a handler factory
+// called once at registration time with a nil error to produce the handler
+// actually served; it lets setup code build the real handler and an error
+// page handler from the same function.
+type errorFactoryAdaptor struct{}
+
+func (errorFactoryAdaptor) Match(fn any) bool {
+	t := reflect.TypeOf(fn)
+	return t != nil && t.Kind() == reflect.Func &&
+		t.NumIn() == 1 && t.In(0) == errType &&
+		t.NumOut() == 1 && t.Out(0) == httpHandlerType
+}
+
+func (errorFactoryAdaptor) Adapt(fn any) (http.Handler, error) {
+	out := reflect.ValueOf(fn).Call([]reflect.Value{reflect.Zero(errType)})
+	handler, _ := out[0].Interface().(http.Handler)
+	if handler == nil {
+		return nil, errNilHandler
+	}
+	return handler, nil
+}
+
+// pathParamsFuncAdaptor adapts func(http.ResponseWriter, *http.Request,
+// PathParams).
+type pathParamsFuncAdaptor struct{}
+
+func (pathParamsFuncAdaptor) Match(fn any) bool {
+	t := reflect.TypeOf(fn)
+	return t != nil && t.Kind() == reflect.Func &&
+		t.NumIn() == 3 && t.In(0) == responseWriteType && t.In(1) == requestType && t.In(2) == pathParamsType &&
+		t.NumOut() == 0
+}
+
+func (pathParamsFuncAdaptor) Adapt(fn any) (http.Handler, error) {
+	// The signature is fully concrete once Match has confirmed it, so the
+	// type assertion below pays the reflection cost once here, at
+	// registration time, rather than on every request.
+	f := fn.(func(http.ResponseWriter, *http.Request, PathParams))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f(w, r, PathParams{r})
+	}), nil
+}
+
+// jsonAdaptor adapts func(context.Context, in) (out, error), where in and
+// out are pointers to struct types, decoding the request body into in as
+// JSON and encoding out as the JSON response body. Unlike the other
+// adaptors, it still calls fn through reflect.Value.Call on every request:
+// in's concrete type varies per registration, so there is no single
+// function signature to type-assert to ahead of time the way
+// pathParamsFuncAdaptor does.
+type jsonAdaptor struct{}
+
+func (jsonAdaptor) Match(fn any) bool {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func || t.NumIn() != 2 || t.NumOut() != 2 {
+		return false
+	}
+	return t.In(0) == contextType &&
+		t.In(1).Kind() == reflect.Ptr && t.In(1).Elem().Kind() == reflect.Struct &&
+		t.Out(0).Kind() == reflect.Ptr && t.Out(0).Elem().Kind() == reflect.Struct &&
+		t.Out(1) == errType
+}
+
+func (jsonAdaptor) Adapt(fn any) (http.Handler, error) {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	reqType := t.In(1).Elem()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := reflect.New(reqType)
+		if r.Body != nil {
+			if err := json.NewDecoder(r.Body).Decode(req.Interface()); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		out := v.Call([]reflect.Value{reflect.ValueOf(r.Context()), req})
+		if err, _ := out[1].Interface().(error); err != nil {
+			status := http.StatusInternalServerError
+			if he, ok := err.(HTTPError); ok {
+				status = he.StatusCode()
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out[0].Interface())
+	}), nil
+}
+
+func init() {
+	RegisterAdaptor(handlerAdaptor{})
+	RegisterAdaptor(handlerFuncAdaptor{})
+	RegisterAdaptor(errorFactoryAdaptor{})
+	RegisterAdaptor(pathParamsFuncAdaptor{})
+	RegisterAdaptor(jsonAdaptor{})
+}
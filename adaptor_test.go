@@ -0,0 +1,69 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleFuncPlainHandlerFunc(t *testing.T) {
+	g := NewGroup()
+	g.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("plain"))
+	})
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/plain", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if body := w.Body.String(); body != "plain" {
+		t.Fatalf("Expected body %q, got %q", "plain", body)
+	}
+}
+
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+type greetResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+func TestHandleFuncJSON(t *testing.T) {
+	g := NewGroup()
+	g.HandleFunc("/greet", func(ctx context.Context, in *greetRequest) (*greetResponse, error) {
+		return &greetResponse{Greeting: "Hello, " + in.Name}, nil
+	})
+
+	mux := g.Compile()
+	body, _ := json.Marshal(greetRequest{Name: "World"})
+	req := httptest.NewRequest(http.MethodPost, "/greet", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	var resp greetResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %s", err)
+	}
+	if resp.Greeting != "Hello, World" {
+		t.Fatalf("Expected greeting %q, got %q", "Hello, World", resp.Greeting)
+	}
+}
+
+func TestHandleFuncNoAdaptor(t *testing.T) {
+	g := NewGroup()
+	g.HandleFunc("/bad", 42)
+
+	_, err := g.Build()
+	if err == nil {
+		t.Fatal("Expected Build to return an error for a handler func with no matching adaptor, but it didn't")
+	}
+	if !errors.Is(err, errNoAdaptor) {
+		t.Fatalf("Expected err to wrap errNoAdaptor, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Matcher reports whether a request should be handled by the route it is
+// attached to. Matchers are evaluated against the request as it stands at
+// the point the route is reached, which may have been rewritten by an
+// earlier route in the same walk; see Group.When.
+type Matcher interface {
+	Match(*http.Request) bool
+}
+
+type matcherFunc func(*http.Request) bool
+
+func (f matcherFunc) Match(r *http.Request) bool {
+	return f(r)
+}
+
+// Host matches requests whose Host header equals host exactly.
+func Host(host string) Matcher {
+	return matcherFunc(func(r *http.Request) bool {
+		return r.Host == host
+	})
+}
+
+// Method matches requests using one of the given HTTP methods.
+func Method(methods ...string) Matcher {
+	return matcherFunc(func(r *http.Request) bool {
+		for _, m := range methods {
+			if r.Method == m {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Header matches requests carrying the given header value.
+func Header(key, value string) Matcher {
+	return matcherFunc(func(r *http.Request) bool {
+		return r.Header.Get(key) == value
+	})
+}
+
+// Query matches requests whose URL query carries the given value for key.
+func Query(key, value string) Matcher {
+	return matcherFunc(func(r *http.Request) bool {
+		return r.URL.Query().Get(key) == value
+	})
+}
+
+// PathPrefix matches requests whose URL path starts with prefix.
+func PathPrefix(prefix string) Matcher {
+	return matcherFunc(func(r *http.Request) bool {
+		return strings.HasPrefix(r.URL.Path, prefix)
+	})
+}
+
+// AnyOf matches if at least one of m matches.
+func AnyOf(m ...Matcher) Matcher {
+	return matcherFunc(func(r *http.Request) bool {
+		for _, mm := range m {
+			if mm.Match(r) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// AllOf matches if every one of m matches.
+func AllOf(m ...Matcher) Matcher {
+	return matcherFunc(func(r *http.Request) bool {
+		for _, mm := range m {
+			if !mm.Match(r) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Not inverts m.
+func Not(m Matcher) Matcher {
+	return matcherFunc(func(r *http.Request) bool {
+		return !m.Match(r)
+	})
+}
+
+// matchedRoute pairs a Route with the Matcher that must match for it to run.
+type matchedRoute struct {
+	Matcher Matcher
+	Route   Route
+}
+
+// When adds h to the group guarded by m: h's handler chain only runs for
+// requests m matches, and unlike the plain path routes added by Handle, it
+// is evaluated by a sequential walker rather than registered on the mux
+// directly (see Build). This lets a route rewrite the request, via
+// Rewrite, and have that rewrite observed by the routes that follow it in
+// the same group, making Group usable as a redirect/rewrite pipeline.
+func (g *Group) When(m Matcher, h ...Router) *Group {
+	if m == nil {
+		g.fail(errNilMatcher)
+		return g
+	}
+	for _, obj := range h {
+		var routes []Route
+		switch t := obj.(type) {
+		case Route:
+			routes = []Route{t}
+		case *Group:
+			routes = t.Routes()
+		default:
+			g.fail(fmt.Errorf("%T:%w", t, errSwitchDefault))
+			continue
+		}
+		for _, rt := range routes {
+			g.matched = append(g.matched, matchedRoute{Matcher: m, Route: rt})
+		}
+	}
+	return g
+}
+
+// matchedRoutes applies the group's middleware to every route added with
+// When, mirroring the wrapping Routes does for plain routes.
+func (g *Group) matchedRoutes() []matchedRoute {
+	for i := range g.matched {
+		handler := g.matched[i].Route.Handler
+		if handler == nil {
+			g.fail(errNilFunc)
+			continue
+		}
+		for j := len(g.mwares) - 1; j >= 0; j-- {
+			handler = g.mwares[j](handler)
+			if handler == nil {
+				g.fail(errFuncReturnNil)
+				break
+			}
+		}
+		if handler == nil {
+			continue
+		}
+		g.matched[i].Route.Handler = g.wrapUse(handler)
+	}
+	return g.matched
+}
+
+// rewriteBoxKey carries the mutable box rewrites are written to during a
+// single walk so that a route further along the walk observes them.
+type rewriteBoxKey struct{}
+
+// requestBox lets Rewrite hand a rewritten *http.Request back to the walker
+// that invoked the route currently running, without requiring Rewrite to
+// know it is running inside a walk at all.
+type requestBox struct {
+	r *http.Request
+}
+
+// walker builds the root handler Compile mounts for routes added with When.
+// It evaluates each route's Matcher against the request in insertion order,
+// runs the first one that matches, and stops there unless that route's
+// handler chain wrote nothing to the response (route.ResponseWriter.Written
+// reports false), in which case the walk continues to the next matching
+// route carrying forward any rewrite that handler chain made.
+func walker(matched []matchedRoute) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw, ok := w.(ResponseWriter)
+		if !ok {
+			rw = wrap(w)
+		}
+
+		box := &requestBox{r: r}
+		current := r.WithContext(context.WithValue(r.Context(), rewriteBoxKey{}, box))
+
+		for _, mr := range matched {
+			if !mr.Matcher.Match(current) {
+				continue
+			}
+			box.r = current
+			mr.Route.Handler.ServeHTTP(rw, current)
+			if rw.Written() {
+				return
+			}
+			current = box.r
+		}
+		http.NotFound(w, r)
+	})
+}
+
+// Rewrite is a route.Middleware that replaces the first occurrence of
+// pattern in the request URL path with replacement before calling next. It
+// never mutates the *http.Request it is given; a shallow copy (via
+// Request.WithContext) with its own copy of the URL carries the replaced
+// path to next. Used inside a Group
+// built with When, a rewrite is visible to every route that follows it in
+// the same walk, even if next itself writes no response.
+func Rewrite(pattern, replacement string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.URL.Path, pattern) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rewritten := r.WithContext(r.Context())
+			u := *r.URL
+			u.Path = strings.Replace(r.URL.Path, pattern, replacement, 1)
+			rewritten.URL = &u
+			if box, ok := r.Context().Value(rewriteBoxKey{}).(*requestBox); ok {
+				box.r = rewritten
+			}
+			next.ServeHTTP(w, rewritten)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package route
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWhenMatchesAndRuns(t *testing.T) {
+	g := NewGroup()
+	g.When(PathPrefix("/api"), Define("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("api"))
+	})))
+
+	mux := g.Compile()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/widgets", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if body := w.Body.String(); body != "api" {
+		t.Fatalf("Expected body %q, got %q", "api", body)
+	}
+}
+
+func TestWhenFallsThroughToNotFound(t *testing.T) {
+	g := NewGroup()
+	g.When(PathPrefix("/api"), Define("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("api"))
+	})))
+
+	mux := g.Compile()
+
+	req := httptest.NewRequest(http.MethodGet, "/elsewhere", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRewriteVisibleToLaterRoute(t *testing.T) {
+	g := NewGroup()
+
+	g.When(PathPrefix("/old"), Define("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		Wrap(Rewrite("/old", "/new")))
+
+	g.When(PathPrefix("/new"), Define("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("new: " + r.URL.Path))
+	})))
+
+	mux := g.Compile()
+
+	req := httptest.NewRequest(http.MethodGet, "/old/thing", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	expected := "new: /new/thing"
+	if body := w.Body.String(); body != expected {
+		t.Fatalf("Expected body %q, got %q", expected, body)
+	}
+}
+
+func TestMatcherCombinators(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+
+	if !AllOf(Method(http.MethodGet), PathPrefix("/admin")).Match(req) {
+		t.Fatal("Expected AllOf(GET, /admin prefix) to match")
+	}
+	if AllOf(Method(http.MethodPost), PathPrefix("/admin")).Match(req) {
+		t.Fatal("Expected AllOf(POST, /admin prefix) not to match a GET request")
+	}
+	if !AnyOf(Method(http.MethodPost), PathPrefix("/admin")).Match(req) {
+		t.Fatal("Expected AnyOf(POST, /admin prefix) to match on the path alone")
+	}
+	if !Not(Method(http.MethodPost)).Match(req) {
+		t.Fatal("Expected Not(POST) to match a GET request")
+	}
+}
+
+func TestWhenCatchAllDoesNotShadowMethodRouting(t *testing.T) {
+	g := NewGroup()
+	g.Get("/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("list"))
+	}))
+	g.Post("/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("create"))
+	}))
+	g.When(PathPrefix("/api"), Define("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("api"))
+	})))
+
+	mux := g.Compile()
+
+	req := httptest.NewRequest(http.MethodDelete, "/widgets", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected status %d, got %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+	if allow := resp.Header.Get("Allow"); allow == "" {
+		t.Fatal("Expected an Allow header naming the registered methods")
+	}
+}
+
+func TestWhenConflictsWithRootRoute(t *testing.T) {
+	g := NewGroup()
+	g.Handle(Define("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	g.When(PathPrefix("/api"), Define("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	mux, err := g.Build()
+	if err == nil {
+		t.Fatal("Expected Build to return an error when a When walker collides with a route already at \"/\"")
+	}
+	if mux != nil {
+		t.Fatal("Expected Build to return a nil mux alongside the error")
+	}
+	if !errors.Is(err, errRootRouteUsed) {
+		t.Fatalf("Expected err to wrap errRootRouteUsed, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	route "github.com/8i8/route"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	g := route.NewGroup()
+	g.Wrap(CORS(CORSOptions{AllowedOrigins: []string{"https://example.com"}}))
+	g.Handle(route.Define("/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("widgets"))
+	})))
+
+	mux := g.Compile()
+
+	req := httptest.NewRequest(http.MethodOptions, "/widgets", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://example.com" {
+		t.Fatalf("Expected Access-Control-Allow-Origin to be echoed, got %q", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if w.Body.Len() != 0 {
+		t.Fatalf("Expected an empty preflight body, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	route "github.com/8i8/route"
+)
+
+// Gzip returns a route.Middleware that compresses the response body with
+// gzip if the request's Accept-Encoding header contains "gzip", otherwise
+// with deflate if it contains "deflate", leaving the response untouched if
+// it contains neither. It drops
+// any Content-Length the handler sets, since compression changes the body
+// size, and skips compression for a 204 or 304 response, which must carry
+// no body at all.
+func Gzip() route.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accept := r.Header.Get("Accept-Encoding")
+			switch {
+			case strings.Contains(accept, "gzip"):
+				gw := gzip.NewWriter(w)
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Header().Add("Vary", "Accept-Encoding")
+				cw := newCompressedWriter(w, gw)
+				next.ServeHTTP(cw, r)
+				if !isSkipped(cw) {
+					gw.Close()
+				}
+			case strings.Contains(accept, "deflate"):
+				fw, err := flate.NewWriter(w, flate.DefaultCompression)
+				if err != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+				w.Header().Set("Content-Encoding", "deflate")
+				w.Header().Add("Vary", "Accept-Encoding")
+				cw := newCompressedWriter(w, fw)
+				next.ServeHTTP(cw, r)
+				if !isSkipped(cw) {
+					fw.Close()
+				}
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// flusher is implemented by both *gzip.Writer and *flate.Writer; neither
+// satisfies http.Flusher directly since their Flush returns an error.
+type flusher interface {
+	Flush() error
+}
+
+// compressedWriter sends the response body through writer (a gzip or flate
+// Writer) instead of straight to the wrapped http.ResponseWriter. It strips
+// any Content-Length the handler sets, whether before the first Write or as
+// part of a later WriteHeader (the case http.ServeContent and
+// http.FileServer use), since compression changes the body length. A 204 or
+// 304 response carries no body, so it bypasses the compressor entirely
+// rather than writing out an empty gzip/flate stream where none should be.
+type compressedWriter struct {
+	http.ResponseWriter
+	writer io.Writer
+	bypass bool
+}
+
+func (w *compressedWriter) WriteHeader(status int) {
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		w.bypass = true
+		w.Header().Del("Content-Encoding")
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *compressedWriter) Write(b []byte) (int, error) {
+	if w.bypass {
+		return w.ResponseWriter.Write(b)
+	}
+	w.Header().Del("Content-Length")
+	return w.writer.Write(b)
+}
+
+// Flush flushes the compressor's internal buffer through to the underlying
+// http.ResponseWriter, and flushes that writer too if it supports
+// http.Flusher, so a streaming handler downstream of Gzip still sees its
+// writes reach the client promptly.
+func (w *compressedWriter) Flush() {
+	if f, ok := w.writer.(flusher); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type hijackerMixin struct{ h http.Hijacker }
+
+func (m hijackerMixin) Hijack() (net.Conn, *bufio.ReadWriter, error) { return m.h.Hijack() }
+
+type pusherMixin struct{ p http.Pusher }
+
+func (m pusherMixin) Push(target string, opts *http.PushOptions) error {
+	return m.p.Push(target, opts)
+}
+
+// skipper is implemented by compressedWriter and promoted through every
+// combination newCompressedWriter returns, letting Gzip ask after
+// next.ServeHTTP returns whether the response bypassed compression, without
+// needing to know which combination it handed next.
+type skipper interface {
+	skipped() bool
+}
+
+func (w *compressedWriter) skipped() bool { return w.bypass }
+
+func isSkipped(w http.ResponseWriter) bool {
+	s, ok := w.(skipper)
+	return ok && s.skipped()
+}
+
+// newCompressedWriter builds the narrowest http.ResponseWriter that both
+// compresses the body and still exposes exactly the optional interfaces
+// (http.Hijacker, http.Pusher) that w itself implements, the same approach
+// responsewriter.go's wrap takes for http.Flusher, so a type assertion
+// against one of those interfaces downstream of Gzip gets an honest answer.
+// Flush is always present, regardless of whether w is an http.Flusher,
+// since it also needs to flush the compressor's own buffer.
+func newCompressedWriter(w http.ResponseWriter, writer io.Writer) http.ResponseWriter {
+	base := &compressedWriter{ResponseWriter: w, writer: writer}
+	h, isHijacker := w.(http.Hijacker)
+	p, isPusher := w.(http.Pusher)
+
+	switch {
+	case isHijacker && isPusher:
+		return &struct {
+			*compressedWriter
+			hijackerMixin
+			pusherMixin
+		}{base, hijackerMixin{h}, pusherMixin{p}}
+	case isHijacker:
+		return &struct {
+			*compressedWriter
+			hijackerMixin
+		}{base, hijackerMixin{h}}
+	case isPusher:
+		return &struct {
+			*compressedWriter
+			pusherMixin
+		}{base, pusherMixin{p}}
+	default:
+		return base
+	}
+}
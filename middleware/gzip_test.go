@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	route "github.com/8i8/route"
+)
+
+func TestGzipCompressesWhenAccepted(t *testing.T) {
+	g := route.NewGroup()
+	g.Wrap(Gzip())
+	g.Handle(route.Define("/hello", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello, gzip"))
+	})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected Content-Encoding gzip, got %q", w.Header().Get("Content-Encoding"))
+	}
+
+	gr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Expected a valid gzip body: %s", err)
+	}
+	body, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("Failed to decompress body: %s", err)
+	}
+	if string(body) != "hello, gzip" {
+		t.Fatalf("Expected decompressed body %q, got %q", "hello, gzip", body)
+	}
+}
+
+func TestGzipPassesThroughWithoutAcceptEncoding(t *testing.T) {
+	g := route.NewGroup()
+	g.Wrap(Gzip())
+	g.Handle(route.Define("/hello", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("plain"))
+	})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("Expected no Content-Encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if w.Body.String() != "plain" {
+		t.Fatalf("Expected body %q, got %q", "plain", w.Body.String())
+	}
+}
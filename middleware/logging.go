@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	route "github.com/8i8/route"
+)
+
+// Logging returns a route.Middleware that writes one line per request to
+// logger once the handler chain has finished, recording method, path,
+// status, response size and latency. Status and size come from
+// route.FromContext, so it relies on Compile having already wrapped the
+// response in a route.ResponseWriter. If logger is nil, log.Default is
+// used.
+func Logging(logger *log.Logger) route.Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			next.ServeHTTP(w, r)
+
+			var status, size int
+			if rw, ok := route.FromContext(r); ok {
+				status = rw.Status()
+				size = rw.Size()
+			}
+			logger.Printf("method=%s path=%s status=%d size=%d duration=%s",
+				r.Method, r.URL.Path, status, size, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	route "github.com/8i8/route"
+)
+
+func TestLoggingRecordsStatusAndSize(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+
+	g := route.NewGroup()
+	g.Wrap(Logging(logger))
+	g.Handle(route.Define("/hello", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hi"))
+	})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	line := logs.String()
+	if !strings.Contains(line, "status=200") || !strings.Contains(line, "size=2") {
+		t.Fatalf("Expected log line to report status=200 size=2, got %q", line)
+	}
+}
@@ -0,0 +1,6 @@
+// Package middleware provides production-grade route.Middleware
+// implementations: panic recovery, structured access logging, CORS, gzip
+// and deflate content encoding, a request-ID tracer and a request timeout.
+// Each is a plain route.Middleware and composes with the rest of the route
+// package via route.Wrap.
+package middleware
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	route "github.com/8i8/route"
+)
+
+// Recovery returns a route.Middleware that recovers a panic raised while
+// serving a request, logs it with its stack trace via logger, and responds
+// 500 instead of taking the process down. Unlike a route.ConfigError, which
+// reports misconfiguration detected while building a Group, a recovered
+// panic is a per-request failure and must not be fatal. If logger is nil,
+// log.Default is used.
+func Recovery(logger *log.Logger) route.Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				logger.Printf("route/middleware: panic: %v\n%s", rec, debug.Stack())
+				if rw, ok := route.FromContext(r); ok && rw.Written() {
+					return
+				}
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
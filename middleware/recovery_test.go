@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	route "github.com/8i8/route"
+)
+
+func TestRecoveryCatchesPanic(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+
+	g := route.NewGroup()
+	g.Wrap(Recovery(logger))
+	g.Handle(route.Define("/boom", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("kaboom")
+	})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if logs.Len() == 0 {
+		t.Fatal("Expected the panic to be logged")
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	route "github.com/8i8/route"
+)
+
+func TestRequestIDGeneratedAndEchoed(t *testing.T) {
+	var seen string
+
+	g := route.NewGroup()
+	g.Wrap(RequestID())
+	g.Handle(route.Define("/hello", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if seen == "" {
+		t.Fatal("Expected a request ID to be available from the handler's context")
+	}
+	if w.Header().Get(RequestIDHeader) != seen {
+		t.Fatalf("Expected response header %q to echo %q, got %q", RequestIDHeader, seen, w.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestRequestIDReusesIncoming(t *testing.T) {
+	g := route.NewGroup()
+	g.Wrap(RequestID())
+	g.Handle(route.Define("/hello", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	req.Header.Set(RequestIDHeader, "client-supplied-id")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Header().Get(RequestIDHeader) != "client-supplied-id" {
+		t.Fatalf("Expected the client-supplied ID to be echoed, got %q", w.Header().Get(RequestIDHeader))
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	route "github.com/8i8/route"
+)
+
+// Timeout returns a route.Middleware that responds with msg and a 503 if
+// next has not returned within d. It is a thin wrapper around
+// http.TimeoutHandler, which buffers next's writes and only forwards them to
+// the real ResponseWriter once next finishes within the deadline, so it
+// cooperates correctly with the route.ResponseWriter Compile installs: the
+// wrapper only ever observes the writes that actually reach the client.
+func Timeout(d time.Duration, msg string) route.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.TimeoutHandler(next, d, msg)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	route "github.com/8i8/route"
+)
+
+func TestTimeoutRespondsWhenHandlerIsSlow(t *testing.T) {
+	g := route.NewGroup()
+	g.Wrap(Timeout(10*time.Millisecond, "timed out"))
+	g.Handle(route.Define("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte("too late"))
+	})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
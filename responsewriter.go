@@ -0,0 +1,168 @@
+package route
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter extends http.ResponseWriter with introspection middleware
+// needs to avoid acting twice on the same response, the double-execution bug
+// that motivated this type: logging/gzip/recovery middleware can check
+// Written() before touching the body instead of guessing from side effects.
+type ResponseWriter interface {
+	http.ResponseWriter
+	// Status reports the status code passed to WriteHeader, 200 if the first
+	// Write came without a WriteHeader, or 0 if nothing has been written yet.
+	Status() int
+	// Written reports whether a status line has gone out, whether via
+	// WriteHeader or an implicit 200 from the first Write.
+	Written() bool
+	// Size reports the number of response body bytes written so far.
+	Size() int
+	// Unwrap returns the underlying http.ResponseWriter.
+	Unwrap() http.ResponseWriter
+}
+
+// responseWriter is the base ResponseWriter implementation; flush, hijack
+// and push support are added on top of it by wrap, conditional on the
+// underlying http.ResponseWriter actually supporting them.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	size    int
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.written {
+		w.status = status
+		w.written = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.status = http.StatusOK
+		w.written = true
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+func (w *responseWriter) Status() int                 { return w.status }
+func (w *responseWriter) Written() bool               { return w.written }
+func (w *responseWriter) Size() int                   { return w.size }
+func (w *responseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+type flusherMixin struct{ f http.Flusher }
+
+func (m flusherMixin) Flush() { m.f.Flush() }
+
+type hijackerMixin struct{ h http.Hijacker }
+
+func (m hijackerMixin) Hijack() (net.Conn, *bufio.ReadWriter, error) { return m.h.Hijack() }
+
+type pusherMixin struct{ p http.Pusher }
+
+func (m pusherMixin) Push(target string, opts *http.PushOptions) error {
+	return m.p.Push(target, opts)
+}
+
+// wrap builds the narrowest ResponseWriter that both tracks status/size and
+// still exposes exactly the optional interfaces (http.Flusher,
+// http.Hijacker, http.Pusher) that w itself implements, so a type assertion
+// against one of those interfaces downstream gets an honest answer instead
+// of a method that silently no-ops.
+func wrap(w http.ResponseWriter) ResponseWriter {
+	base := &responseWriter{ResponseWriter: w}
+	f, isFlusher := w.(http.Flusher)
+	h, isHijacker := w.(http.Hijacker)
+	p, isPusher := w.(http.Pusher)
+
+	switch {
+	case isFlusher && isHijacker && isPusher:
+		return &struct {
+			*responseWriter
+			flusherMixin
+			hijackerMixin
+			pusherMixin
+		}{base, flusherMixin{f}, hijackerMixin{h}, pusherMixin{p}}
+	case isFlusher && isHijacker:
+		return &struct {
+			*responseWriter
+			flusherMixin
+			hijackerMixin
+		}{base, flusherMixin{f}, hijackerMixin{h}}
+	case isFlusher && isPusher:
+		return &struct {
+			*responseWriter
+			flusherMixin
+			pusherMixin
+		}{base, flusherMixin{f}, pusherMixin{p}}
+	case isHijacker && isPusher:
+		return &struct {
+			*responseWriter
+			hijackerMixin
+			pusherMixin
+		}{base, hijackerMixin{h}, pusherMixin{p}}
+	case isFlusher:
+		return &struct {
+			*responseWriter
+			flusherMixin
+		}{base, flusherMixin{f}}
+	case isHijacker:
+		return &struct {
+			*responseWriter
+			hijackerMixin
+		}{base, hijackerMixin{h}}
+	case isPusher:
+		return &struct {
+			*responseWriter
+			pusherMixin
+		}{base, pusherMixin{p}}
+	default:
+		return base
+	}
+}
+
+// responseWriterKey is the context key WithWriter stores the current
+// ResponseWriter under.
+type responseWriterKey struct{}
+
+// FromContext returns the ResponseWriter Compile wrapped the response in for
+// the current request, so that middleware holding only a *http.Request
+// (rather than the http.ResponseWriter passed down the call stack) can still
+// check Written()/Status()/Size().
+func FromContext(r *http.Request) (ResponseWriter, bool) {
+	rw, ok := r.Context().Value(responseWriterKey{}).(ResponseWriter)
+	return rw, ok
+}
+
+// WithWriter is the route.Middleware Compile inserts as the outermost layer
+// of every route: it wraps w in a ResponseWriter, stores it in the request
+// context for FromContext, and passes the wrapped writer down the rest of
+// the chain in place of w.
+func WithWriter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := wrap(w)
+		ctx := context.WithValue(r.Context(), responseWriterKey{}, rw)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
+
+// guardNext wraps next so that it is skipped once the response has already
+// been written, letting Group.Use middleware short-circuit the rest of the
+// chain simply by writing a response, without having to remember to omit
+// its own call to next.
+func guardNext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rw, ok := FromContext(r); ok && rw.Written() {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
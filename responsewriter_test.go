@@ -0,0 +1,103 @@
+package route
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithWriterTracksStatusAndSize(t *testing.T) {
+	g := NewGroup()
+	g.Handle(Define("/status", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw, ok := FromContext(r)
+		if !ok {
+			t.Fatal("Expected FromContext to find a ResponseWriter")
+		}
+		if rw.Written() {
+			t.Fatal("Expected Written() to be false before anything is written")
+		}
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("short and stout"))
+		if !rw.Written() {
+			t.Fatal("Expected Written() to be true after WriteHeader")
+		}
+		if rw.Status() != http.StatusTeapot {
+			t.Fatalf("Expected status %d, got %d", http.StatusTeapot, rw.Status())
+		}
+		if rw.Size() != len("short and stout") {
+			t.Fatalf("Expected size %d, got %d", len("short and stout"), rw.Size())
+		}
+	})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("Expected recorded status %d, got %d", http.StatusTeapot, w.Code)
+	}
+}
+
+// hijackableRecorder adds a no-op Hijack to httptest.ResponseRecorder so the
+// conditional-interface wiring in wrap can be exercised.
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+
+func TestWrapExposesFlushAndHijackConditionally(t *testing.T) {
+	plain := wrap(httptest.NewRecorder())
+	if _, ok := plain.(http.Flusher); !ok {
+		t.Fatal("Expected httptest.ResponseRecorder to be wrapped as a Flusher")
+	}
+	if _, ok := plain.(http.Hijacker); ok {
+		t.Fatal("Expected the plain recorder not to be wrapped as a Hijacker")
+	}
+
+	hijackable := wrap(hijackableRecorder{httptest.NewRecorder()})
+	if _, ok := hijackable.(http.Hijacker); !ok {
+		t.Fatal("Expected a recorder implementing Hijacker to be wrapped as one")
+	}
+	if _, ok := hijackable.(http.Flusher); !ok {
+		t.Fatal("Expected a recorder implementing Flusher to still be wrapped as one")
+	}
+}
+
+func TestUseShortCircuitsChain(t *testing.T) {
+	g := NewGroup()
+
+	var handlerCalled bool
+	denyAuth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			next.ServeHTTP(w, r) // forgetting to return is exactly the bug Use guards against
+		})
+	}
+
+	g.Use(denyAuth)
+	g.Handle(Define("/secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+		_, _ = w.Write([]byte("top secret"))
+	})))
+
+	mux := g.Compile()
+	req := httptest.NewRequest(http.MethodGet, "/secret", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if handlerCalled {
+		t.Fatal("Expected the route handler not to run once Use middleware had already written a response")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	if w.Body.String() != "" {
+		t.Fatalf("Expected empty body, got %q", w.Body.String())
+	}
+}
@@ -8,11 +8,8 @@ package route
 import (
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	"os"
 	"runtime"
-	"strconv"
 	"strings"
 )
 
@@ -25,9 +22,12 @@ type Router interface {
 // of route middleware.
 type Middleware func(http.Handler) http.Handler
 
-// Route comprises of a path and handler.
+// Route comprises of a path, an optional method and a handler. When Method
+// is empty the route matches the path regardless of HTTP method, preserving
+// the historical behaviour of the package.
 type Route struct {
 	Path    string
+	Method  string
 	Handler http.Handler
 }
 
@@ -36,9 +36,15 @@ func (r Route) Routes() []Route {
 	return []Route{r}
 }
 
-// Define returns a route.
+// Define returns a route that matches path regardless of HTTP method.
 func Define(path string, handle http.Handler) Route {
-	return Route{path, handle}
+	return Route{Path: path, Handler: handle}
+}
+
+// DefineMethod returns a route that only matches path when requested with
+// method.
+func DefineMethod(method, path string, handle http.Handler) Route {
+	return Route{Path: path, Method: method, Handler: handle}
 }
 
 // Wrap wraps middleware, returning a single function with all the provided
@@ -57,7 +63,7 @@ func (r Route) Wrap(funcs ...Middleware) Route {
 	for i := len(funcs) - 1; i >= 0; i-- {
 		r.Handler = funcs[i](r.Handler)
 	}
-	return Route{r.Path, r.Handler}
+	return Route{r.Path, r.Method, r.Handler}
 }
 
 // Group simplifies route composition by permitting the selective and
@@ -66,9 +72,12 @@ func (r Route) Wrap(funcs ...Middleware) Route {
 // subgroups, enabling the selective application of middleware to subgroups
 // within a group rather than globally.
 type Group struct {
-	Mux    *http.ServeMux
-	mwares []Middleware
-	routes []Route
+	Mux     *http.ServeMux
+	mwares  []Middleware
+	uses    []Middleware
+	routes  []Route
+	matched []matchedRoute
+	errs    []error
 }
 
 func NewGroup() *Group {
@@ -76,43 +85,41 @@ func NewGroup() *Group {
 }
 
 var (
-	errHandlerUsed    = errors.New("http.Handle passed into middleware Wrap")
-	errHandleFormat   = errors.New("format err, should be (<path>, <handler>) pairs")
 	errSwitchDefault  = errors.New("switch default, unknown type")
 	errNilFunc        = errors.New("nil returned from HandlerFunc in chain")
 	errNilHandlerFunc = errors.New("nil HandlerFunc")
 	errNilHandler     = errors.New("nil http.Handler")
 	errNilMiddleware  = errors.New("nil route.Middleware")
-	errNilGroup       = errors.New("nil route.Group")
 	errFuncReturnNil  = errors.New("function returned nil")
-	errGroupUsed      = errors.New("want *route.Group not route.Group")
+	errNilAdaptor     = errors.New("nil route.Adaptor")
+	errNoAdaptor      = errors.New("no registered route.Adaptor matches handler func")
+	errNilMatcher     = errors.New("nil route.Matcher")
+	errRootRouteUsed  = errors.New(`route "/" already registered; cannot also mount the When walker there`)
 )
 
-// exitWithLog logs the error message and exits with code 0.
-func exitWithLog(msg string) {
-	_, file, line, _ := runtime.Caller(1) // Get caller info
-	_ = log.Output(3, file+":"+strconv.Itoa(line)+": "+msg)
-	os.Exit(0)
+// ConfigError is a single problem found while building a Group, with the
+// caller file and line at which it was detected. Build collects every
+// ConfigError it finds and returns them joined with errors.Join, rather than
+// terminating the process the way the package used to.
+type ConfigError struct {
+	File string
+	Line int
+	Err  error
 }
 
-// exitWithError logs the error message and exits with code 1.
-func exitWithError(err error) {
-	_, file, line, _ := runtime.Caller(1) // Get caller info
-	_ = log.Output(3, file+":"+strconv.Itoa(line)+": "+err.Error())
-	os.Exit(1)
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
 }
 
-var exit func(error)
-
-func init() {
-	exit = exitWithError
+func (e *ConfigError) Unwrap() error {
+	return e.Err
 }
 
-func fname() string {
-	pc, _, _, _ := runtime.Caller(1)
-	fullName := runtime.FuncForPC(pc).Name()
-	parts := strings.Split(fullName, ".")
-	return parts[len(parts)-1] // Extract only the function name
+// fail records err as a ConfigError against g, captured at the call site of
+// fail itself.
+func (g *Group) fail(err error) {
+	_, file, line, _ := runtime.Caller(1)
+	g.errs = append(g.errs, &ConfigError{File: file, Line: line, Err: err})
 }
 
 func (g *Group) Routes() []Route {
@@ -123,8 +130,10 @@ func (g *Group) Routes() []Route {
 		handler := g.routes[reverseIndex].Handler
 		if handler == nil {
 			// nil values in nested middleware can be very tricky to deal so we
-			// get out fast and check everywhere.
-			exit(errNilFunc)
+			// record it and move on, there is nothing useful left to do with
+			// this route.
+			g.fail(errNilFunc)
+			continue
 		}
 
 		// Apply each middleware to our function
@@ -135,39 +144,220 @@ func (g *Group) Routes() []Route {
 
 			// Check for nil middleware output
 			if handler == nil {
-				exit(errFuncReturnNil)
+				g.fail(errFuncReturnNil)
+				break
 			}
 		}
+		if handler == nil {
+			continue
+		}
 
 		// No server just yet, we need to replace the function with its
 		// wrapped replacement.
-		g.routes[reverseIndex].Handler = handler
+		g.routes[reverseIndex].Handler = g.wrapUse(handler)
 	}
 	return g.routes
 }
 
-// Compile wraps all routes with the appropriate middleware and loads them all
-// into a multiplex server.
-func (g *Group) Compile() *http.ServeMux {
+// wrapUse applies the group's Use middleware to handler, innermost first,
+// each one wrapped so that it is skipped once an earlier one in the chain
+// has already written a response. See Use.
+func (g *Group) wrapUse(handler http.Handler) http.Handler {
+	for i := len(g.uses) - 1; i >= 0; i-- {
+		handler = g.uses[i](guardNext(handler))
+		if handler == nil {
+			g.fail(errFuncReturnNil)
+			return nil
+		}
+	}
+	return handler
+}
+
+// Use registers middleware with the same first-in-first-applied ordering as
+// Wrap, but with one difference: if a Use middleware (or anything before it
+// in the chain) has already written to the response, ServeMux-facing code
+// downstream of it is skipped automatically, so auth/ratelimit-style
+// middleware can short-circuit the chain just by calling WriteHeader,
+// without needing to remember to omit its own call to next.
+func (g *Group) Use(mw ...Middleware) *Group {
+	if mw == nil || len(mw) > 0 && mw[0] == nil {
+		g.fail(errNilMiddleware)
+		return g
+	}
+	g.uses = append(g.uses, mw...)
+	return g
+}
+
+// Build wraps all routes with the appropriate middleware and loads them all
+// into a multiplex server, same as Compile, but returns every ConfigError
+// found along the way joined via errors.Join instead of panicking, so that a
+// misconfigured Group can be reported by a library or a test rather than
+// taking the process down. Routes carrying a Method are registered using Go
+// 1.22's "METHOD /path" ServeMux pattern form, which also makes ServeMux
+// itself respond 405 with an Allow header when a path is registered for
+// other methods but not the one requested. Routes without a Method fall back
+// to the plain path form and match any method, as before.
+//
+// Routes added with When are not registered on the mux directly; instead
+// they are mounted as the catch-all handler for "/" and evaluated in
+// insertion order by a root walker, so they only run for requests that no
+// more specific registered path already served. See When for details.
+//
+// Mounting that catch-all alongside Method routes needs one more piece of
+// care: ServeMux prefers "/" over a path's own "GET /x"/"POST /x" patterns
+// for a method neither of those names, since "/" still matches the request
+// while the method-qualified patterns don't, which would otherwise route an
+// unregistered method to the When walker instead of letting ServeMux return
+// its own 405. Build re-registers every such path with no method of its own,
+// pointing at a handler that reproduces that 405/Allow response, so the
+// catch-all only ever sees paths no route claimed at all.
+//
+// If a route is itself registered at the bare path "/", Build records a
+// ConfigError rather than mounting the catch-all on top of it, since a
+// second *http.ServeMux.Handle call on the same pattern panics.
+//
+// Every handler Build registers, whether a plain route or the When walker,
+// is wrapped in WithWriter first, so route.FromContext works for any handler
+// or middleware reached from it.
+func (g *Group) Build() (*http.ServeMux, error) {
 	if g.Mux == nil {
 		g.Mux = &http.ServeMux{}
 	}
+
+	methods := map[string][]string{}
+	bare := map[string]bool{}
+
 	for _, route := range g.Routes() {
-		g.Mux.Handle(route.Path, route.Handler)
+		if route.Handler == nil {
+			// Already recorded by Routes; nothing safe left to register.
+			continue
+		}
+		pattern := route.Path
+		if route.Method != "" {
+			pattern = route.Method + " " + route.Path
+			methods[route.Path] = append(methods[route.Path], route.Method)
+		} else {
+			bare[route.Path] = true
+		}
+		g.Mux.Handle(pattern, WithWriter(route.Handler))
+	}
+
+	if matched := g.matchedRoutes(); len(matched) > 0 {
+		if bare["/"] {
+			g.fail(errRootRouteUsed)
+		} else {
+			g.Mux.Handle("/", WithWriter(walker(matched)))
+			for path, allowed := range methods {
+				if bare[path] {
+					// A bare route already claims every other method at
+					// path; it takes priority over "/" on its own.
+					continue
+				}
+				g.Mux.Handle(path, WithWriter(methodNotAllowedHandler(allowed)))
+			}
+		}
+	}
+
+	if len(g.errs) > 0 {
+		return nil, errors.Join(g.errs...)
+	}
+	return g.Mux, nil
+}
+
+// methodNotAllowedHandler reproduces the 405 response, with an Allow header
+// listing allowed, that ServeMux would give a path whose only registered
+// patterns are method-qualified, for a path where Build must also register a
+// bare entry to keep a When walker's "/" catch-all from intercepting the
+// request first.
+func methodNotAllowedHandler(allowed []string) http.Handler {
+	allow := strings.Join(allowed, ", ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		http.Error(w, "405 method not allowed", http.StatusMethodNotAllowed)
+	})
+}
+
+// Compile is a convenience wrapper around Build for callers, typically
+// main, that want a misconfigured Group to panic rather than be handled as
+// an error.
+func (g *Group) Compile() *http.ServeMux {
+	mux, err := g.Build()
+	if err != nil {
+		panic(err)
+	}
+	return mux
+}
+
+// Must panics with every ConfigError recorded against g so far, joined via
+// errors.Join, or returns g unchanged if there are none. It lets callers
+// that want fail-fast behaviour get it at the point a route is registered,
+// rather than waiting until Build.
+func (g *Group) Must() *Group {
+	if len(g.errs) > 0 {
+		panic(errors.Join(g.errs...))
 	}
-	return g.Mux
+	return g
 }
 
 // Wrap wraps all endpoints in a Group with its provided decorators, they are
 // applied in order, first in first out.
 func (g *Group) Wrap(mw ...Middleware) *Group {
 	if mw == nil || len(mw) > 0 && mw[0] == nil {
-		exit(errNilMiddleware)
+		g.fail(errNilMiddleware)
+		return g
 	}
 	g.mwares = append(g.mwares, mw...)
 	return g
 }
 
+// Method registers handle against path, restricted to the given HTTP
+// method. Handler chosen on mismatch is left to Build, which relies on
+// http.ServeMux's native 405 handling for method-qualified patterns, or, when
+// a When walker is mounted at "/", on its own equivalent 405 handler.
+func (g *Group) Method(method, path string, handle http.Handler) *Group {
+	if handle == nil {
+		g.fail(errNilHandler)
+		return g
+	}
+	g.routes = append(g.routes, Route{Path: path, Method: method, Handler: handle})
+	return g
+}
+
+// Get registers handle against path, restricted to GET.
+func (g *Group) Get(path string, handle http.Handler) *Group {
+	return g.Method(http.MethodGet, path, handle)
+}
+
+// Post registers handle against path, restricted to POST.
+func (g *Group) Post(path string, handle http.Handler) *Group {
+	return g.Method(http.MethodPost, path, handle)
+}
+
+// Put registers handle against path, restricted to PUT.
+func (g *Group) Put(path string, handle http.Handler) *Group {
+	return g.Method(http.MethodPut, path, handle)
+}
+
+// Patch registers handle against path, restricted to PATCH.
+func (g *Group) Patch(path string, handle http.Handler) *Group {
+	return g.Method(http.MethodPatch, path, handle)
+}
+
+// Delete registers handle against path, restricted to DELETE.
+func (g *Group) Delete(path string, handle http.Handler) *Group {
+	return g.Method(http.MethodDelete, path, handle)
+}
+
+// Options registers handle against path, restricted to OPTIONS.
+func (g *Group) Options(path string, handle http.Handler) *Group {
+	return g.Method(http.MethodOptions, path, handle)
+}
+
+// Head registers handle against path, restricted to HEAD.
+func (g *Group) Head(path string, handle http.Handler) *Group {
+	return g.Method(http.MethodHead, path, handle)
+}
+
 // Handle expects either *route.Group, or string http.Handler, string
 // http.HandlerFunc pairs. Middleware applied to subgroups remains exclusive to
 // the subgroup.
@@ -179,7 +369,7 @@ func (g *Group) Handle(h ...Router) *Group {
 		case *Group:
 			g.routes = append(g.routes, t.Routes()...)
 		default:
-			exit(fmt.Errorf("%T:%w", t, errSwitchDefault))
+			g.fail(fmt.Errorf("%T:%w", t, errSwitchDefault))
 		}
 	}
 	return g
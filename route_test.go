@@ -1,8 +1,10 @@
 package route
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -86,18 +88,91 @@ func TestMiddlewareApplication(t *testing.T) {
 func TestInvalidMiddlewareUsage(t *testing.T) {
 	g := NewGroup()
 
-	// Capture exit calls instead of actually exiting
-	var exited bool
-	exit = func(error) {
-		exited = true
+	// Simulating an invalid middleware
+	var badMiddleware Middleware = nil
+	g.Wrap(badMiddleware)
+	g.Handle(Define("/hello", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	mux, err := g.Build()
+	if err == nil {
+		t.Fatal("Expected Build to return an error for a nil middleware, but it didn't")
+	}
+	if mux != nil {
+		t.Fatal("Expected Build to return a nil mux alongside the error")
 	}
 
-	// Simulating an invalid middleware
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("Expected err to unwrap to a *ConfigError, got %T", err)
+	}
+	if !errors.Is(cfgErr.Err, errNilMiddleware) {
+		t.Fatalf("Expected the ConfigError to wrap errNilMiddleware, got %v", cfgErr.Err)
+	}
+}
+
+func TestMustPanicsOnAccumulatedErrors(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected Must to panic once a ConfigError had been recorded")
+		}
+	}()
+
+	g := NewGroup()
 	var badMiddleware Middleware = nil
-	g.Wrap(badMiddleware) // This should trigger a hard failure
+	g.Wrap(badMiddleware).Must()
+}
+
+func TestMethodRouting(t *testing.T) {
+	g := NewGroup()
+	g.Get("/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("list"))
+	}))
+	g.Post("/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("create"))
+	}))
+
+	mux := g.Compile()
+
+	get := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, get)
+	if body := w.Body.String(); body != "list" {
+		t.Fatalf("Expected body %q, got %q", "list", body)
+	}
+
+	post := httptest.NewRequest(http.MethodPost, "/widgets", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, post)
+	if body := w.Body.String(); body != "create" {
+		t.Fatalf("Expected body %q, got %q", "create", body)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	g := NewGroup()
+	g.Get("/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("list"))
+	}))
+	g.Post("/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("create"))
+	}))
+
+	mux := g.Compile()
+
+	req := httptest.NewRequest(http.MethodDelete, "/widgets", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected status %d, got %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
 
-	if !exited {
-		t.Fatal("Expected os.Exit(1) to be called, but it wasn't")
+	allow := resp.Header.Get("Allow")
+	if !strings.Contains(allow, http.MethodGet) || !strings.Contains(allow, http.MethodPost) {
+		t.Fatalf("Expected Allow header to list GET and POST, got %q", allow)
 	}
 }
 